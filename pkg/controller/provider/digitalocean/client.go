@@ -19,7 +19,10 @@ package digitalocean
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/digitalocean/godo"
 	"k8s.io/client-go/util/flowcontrol"
@@ -28,30 +31,197 @@ import (
 	"github.com/gardener/external-dns-management/pkg/dns/provider/raw"
 )
 
+// RateLimiterConfig tunes how DNSClient reacts to DigitalOcean's rate-limit
+// response headers (RateLimit-Remaining / RateLimit-Reset) and 429 Too Many
+// Requests responses. All fields have defaults so operators only need to set
+// the ones they want to override via DNSHandlerConfig properties.
+type RateLimiterConfig struct {
+	// MinQPS is the floor NewDNSClient's rate limiter is throttled down to
+	// while a reset window is being waited out.
+	MinQPS float32
+	// MaxQPS is the rate the limiter is restored to once a reset window has
+	// passed.
+	MaxQPS float32
+	// MaxRetries bounds how many times an idempotent request is retried
+	// after a 429 before giving up.
+	MaxRetries int
+	// MaxSleep bounds how long a single wait for RateLimit-Reset may take,
+	// regardless of what the response header says.
+	MaxSleep time.Duration
+}
+
+// defaultRateLimiterConfig is used whenever the corresponding DNSHandlerConfig
+// property is absent.
+var defaultRateLimiterConfig = RateLimiterConfig{
+	MinQPS:     1,
+	MaxQPS:     10,
+	MaxRetries: 3,
+	MaxSleep:   2 * time.Minute,
+}
+
+// RateLimiterConfigFromProperties reads a RateLimiterConfig from the
+// optional DIGITALOCEAN_RATELIMIT_* properties of c, falling back to
+// defaultRateLimiterConfig for any that are unset.
+func RateLimiterConfigFromProperties(c *provider.DNSHandlerConfig) RateLimiterConfig {
+	cfg := defaultRateLimiterConfig
+
+	if v, ok := c.GetProperty("DIGITALOCEAN_RATELIMIT_MIN_QPS"); ok {
+		if f, err := strconv.ParseFloat(v, 32); err == nil {
+			cfg.MinQPS = float32(f)
+		}
+	}
+	if v, ok := c.GetProperty("DIGITALOCEAN_RATELIMIT_MAX_QPS"); ok {
+		if f, err := strconv.ParseFloat(v, 32); err == nil {
+			cfg.MaxQPS = float32(f)
+		}
+	}
+	if v, ok := c.GetProperty("DIGITALOCEAN_RATELIMIT_MAX_RETRIES"); ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.MaxRetries = n
+		}
+	}
+	if v, ok := c.GetProperty("DIGITALOCEAN_RATELIMIT_MAX_SLEEP"); ok {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.MaxSleep = d
+		}
+	}
+
+	return cfg
+}
+
+// lowQPSThreshold is the remaining-request count below which the limiter is
+// preemptively throttled down, ahead of actually hitting a 429.
+const lowQPSThreshold = 5
+
 type DNSClient struct {
 	raw.Executor
 
-	svc godo.DomainsService
-	metrics     provider.Metrics
-	rateLimiter flowcontrol.RateLimiter
+	svc      godo.DomainsService
+	metrics  provider.Metrics
+	rlConfig RateLimiterConfig
+
+	// limiter is guarded by limiterMu rather than SetQPS, since
+	// flowcontrol.RateLimiter (the interface returned to callers of
+	// NewDNSClient) has no method to change its QPS in place; throttle
+	// instead swaps in a freshly-constructed limiter for the remainder of a
+	// reset window.
+	limiterMu sync.Mutex
+	limiter   flowcontrol.RateLimiter
+
+	throttledUntil time.Time
 }
 
-func NewDNSClient(apiToken string, metrics provider.Metrics, rateLimiter flowcontrol.RateLimiter) *DNSClient {
+func NewDNSClient(apiToken string, metrics provider.Metrics, rateLimiter flowcontrol.RateLimiter, rlConfig RateLimiterConfig) *DNSClient {
 	client := godo.NewFromToken(apiToken)
-	return &DNSClient{svc: client.Domains, metrics: metrics, rateLimiter: rateLimiter}
+	return &DNSClient{svc: client.Domains, metrics: metrics, limiter: rateLimiter, rlConfig: rlConfig}
+}
+
+// accept waits for a token from the currently active rate limiter, which
+// throttle may have swapped out for a slower one.
+func (cl *DNSClient) accept() {
+	cl.limiterMu.Lock()
+	limiter := cl.limiter
+	cl.limiterMu.Unlock()
+
+	limiter.Accept()
+}
+
+// throttle reacts to the rate-limit state of a godo response: it swaps in a
+// limiter throttled down to MinQPS for the remainder of the current reset
+// window once remaining requests drop below lowQPSThreshold, and restores
+// the limiter to MaxQPS once the window has passed.
+func (cl *DNSClient) throttle(resp *godo.Response) {
+	now := time.Now()
+
+	cl.limiterMu.Lock()
+	defer cl.limiterMu.Unlock()
+
+	if !cl.throttledUntil.IsZero() && now.After(cl.throttledUntil) {
+		cl.limiter = flowcontrol.NewTokenBucketRateLimiter(cl.rlConfig.MaxQPS, int(cl.rlConfig.MaxQPS)+1)
+		cl.throttledUntil = time.Time{}
+	}
+
+	if resp == nil {
+		return
+	}
+
+	if resp.Rate.Remaining < lowQPSThreshold && resp.Rate.Reset.Time.After(now) {
+		cl.limiter = flowcontrol.NewTokenBucketRateLimiter(cl.rlConfig.MinQPS, 1)
+		cl.throttledUntil = resp.Rate.Reset.Time
+		cl.metrics.AddRequests(provider.M_THROTTLED, 1)
+	}
+}
+
+// sleepForReset waits out a 429's reset window, bounded by MaxSleep.
+func (cl *DNSClient) sleepForReset(resp *godo.Response) {
+	wait := defaultRateLimiterConfig.MaxSleep
+	if resp != nil {
+		if until := time.Until(resp.Rate.Reset.Time); until > 0 {
+			wait = until
+		}
+	}
+	if wait > cl.rlConfig.MaxSleep {
+		wait = cl.rlConfig.MaxSleep
+	}
+	time.Sleep(wait)
+}
+
+// withRetry runs do, retrying idempotent requests with exponential jitter
+// backoff when godo reports a 429, up to rlConfig.MaxRetries times. On any
+// response it updates the rate limiter based on the rate-limit headers via
+// throttle.
+func (cl *DNSClient) withRetry(do func() (*godo.Response, error)) error {
+	var lastErr error
+	for attempt := 0; attempt <= cl.rlConfig.MaxRetries; attempt++ {
+		resp, err := do()
+		cl.throttle(resp)
+
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if !isTooManyRequests(resp, err) {
+			return err
+		}
+
+		cl.metrics.AddRequests(provider.M_RETRIED, 1)
+		cl.sleepForReset(resp)
+
+		backoff := time.Duration(1<<uint(attempt)) * 100 * time.Millisecond
+		jitter := time.Duration(rand.Int63n(int64(backoff) + 1))
+		time.Sleep(backoff + jitter)
+	}
+	return lastErr
+}
+
+func isTooManyRequests(resp *godo.Response, err error) bool {
+	if resp != nil && resp.StatusCode == 429 {
+		return true
+	}
+	if errResp, ok := err.(*godo.ErrorResponse); ok && errResp.Response != nil {
+		return errResp.Response.StatusCode == 429
+	}
+	return false
 }
 
 func (cl *DNSClient) ListDomains() ([]godo.Domain, error) {
 	cl.metrics.AddRequests(provider.M_LISTZONES, 1)
-	cl.rateLimiter.Accept()
+	cl.accept()
 
 	var domains []godo.Domain
 	opt := &godo.ListOptions{
-		Page: 1,
+		Page:    1,
 		PerPage: 100,
 	}
 	for {
-		doms, resp, err := cl.svc.List(context.Background(), opt)
+		var doms []godo.Domain
+		var resp *godo.Response
+		err := cl.withRetry(func() (*godo.Response, error) {
+			var err error
+			doms, resp, err = cl.svc.List(context.Background(), opt)
+			return resp, err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -73,15 +243,21 @@ func (cl *DNSClient) ListDomains() ([]godo.Domain, error) {
 
 func (cl *DNSClient) ListRecords(domain string) ([]godo.DomainRecord, error) {
 	cl.metrics.AddRequests(provider.M_LISTRECORDS, 1)
-	cl.rateLimiter.Accept()
+	cl.accept()
 
 	var records []godo.DomainRecord
 	opt := &godo.ListOptions{
-		Page: 1,
+		Page:    1,
 		PerPage: 100,
 	}
 	for {
-		recs, resp, err := cl.svc.Records(context.Background(), domain, opt)
+		var recs []godo.DomainRecord
+		var resp *godo.Response
+		err := cl.withRetry(func() (*godo.Response, error) {
+			var err error
+			recs, resp, err = cl.svc.Records(context.Background(), domain, opt)
+			return resp, err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -105,9 +281,12 @@ func (cl *DNSClient) CreateRecord(r raw.Record) error {
 	req := createRecordRequest(r)
 
 	cl.metrics.AddRequests(provider.M_CREATERECORDS, 1)
-	cl.rateLimiter.Accept()
+	cl.accept()
 
-	if _, _, err := cl.svc.CreateRecord(context.Background(), r.(*Record).domain, req); err != nil {
+	// Creating a record is not idempotent, so it is sent once, not via withRetry.
+	_, resp, err := cl.svc.CreateRecord(context.Background(), r.(*Record).domain, req)
+	cl.throttle(resp)
+	if err != nil {
 		return fmt.Errorf("failed to create record of type %q for DNS name %q and value %q: %s", r.GetType(), r.GetDNSName(), r.GetValue(), err)
 	}
 	return nil
@@ -122,9 +301,13 @@ func (cl *DNSClient) UpdateRecord(r raw.Record) error {
 	}
 
 	cl.metrics.AddRequests(provider.M_UPDATERECORDS, 1)
-	cl.rateLimiter.Accept()
+	cl.accept()
 
-	if _, _, err = cl.svc.EditRecord(context.Background(), r.(*Record).domain, id, req); err != nil {
+	err = cl.withRetry(func() (*godo.Response, error) {
+		_, resp, err := cl.svc.EditRecord(context.Background(), r.(*Record).domain, id, req)
+		return resp, err
+	})
+	if err != nil {
 		return fmt.Errorf("failed to update record of type %q for DNS name %q and value %q: %s", r.GetType(), r.GetDNSName(), r.GetValue(), err)
 	}
 	return nil
@@ -137,9 +320,13 @@ func (cl *DNSClient) DeleteRecord(r raw.Record) error {
 	}
 
 	cl.metrics.AddRequests(provider.M_DELETERECORDS, 1)
-	cl.rateLimiter.Accept()
+	cl.accept()
 
-	if _, err = cl.svc.DeleteRecord(context.Background(), r.(*Record).domain, id); err != nil {
+	err = cl.withRetry(func() (*godo.Response, error) {
+		resp, err := cl.svc.DeleteRecord(context.Background(), r.(*Record).domain, id)
+		return resp, err
+	})
+	if err != nil {
 		return fmt.Errorf("failed to delete record of type %q for DNS name %q and value %q: %s", r.GetType(), r.GetDNSName(), r.GetValue(), err)
 	}
 	return nil
@@ -147,10 +334,10 @@ func (cl *DNSClient) DeleteRecord(r raw.Record) error {
 
 func (cl *DNSClient) NewRecord(fqdn, rtype, value string, zone provider.DNSHostedZone, ttl int64) raw.Record {
 	return toRecord(godo.DomainRecord{
-		Type:     rtype,
-		Name:     fqdn,
-		Data:     value,
-		TTL:      int(ttl),
+		Type: rtype,
+		Name: fqdn,
+		Data: value,
+		TTL:  int(ttl),
 	}, zone.Key())
 }
 
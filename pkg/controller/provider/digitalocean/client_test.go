@@ -0,0 +1,140 @@
+/*
+ * Copyright 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ *
+ */
+
+package digitalocean
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/gardener/external-dns-management/pkg/dns/provider"
+)
+
+func TestIsTooManyRequests(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		resp *godo.Response
+		err  error
+		want bool
+	}{
+		{
+			name: "nil response and error",
+			want: false,
+		},
+		{
+			name: "response with 429 status",
+			resp: &godo.Response{Response: &http.Response{StatusCode: 429}},
+			want: true,
+		},
+		{
+			name: "response with other status",
+			resp: &godo.Response{Response: &http.Response{StatusCode: 500}},
+			want: false,
+		},
+		{
+			name: "error response with 429 status",
+			err:  &godo.ErrorResponse{Response: &http.Response{StatusCode: 429}},
+			want: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isTooManyRequests(tc.resp, tc.err); got != tc.want {
+				t.Fatalf("isTooManyRequests() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRateLimiterConfigDefaults(t *testing.T) {
+	if defaultRateLimiterConfig.MinQPS >= defaultRateLimiterConfig.MaxQPS {
+		t.Fatalf("expected MinQPS < MaxQPS, got %v >= %v", defaultRateLimiterConfig.MinQPS, defaultRateLimiterConfig.MaxQPS)
+	}
+	if defaultRateLimiterConfig.MaxRetries <= 0 {
+		t.Fatalf("expected a positive default MaxRetries, got %d", defaultRateLimiterConfig.MaxRetries)
+	}
+	if defaultRateLimiterConfig.MaxSleep <= 0 {
+		t.Fatalf("expected a positive default MaxSleep, got %s", defaultRateLimiterConfig.MaxSleep)
+	}
+}
+
+// TestWithRetryRetriesOn429 drives withRetry through a fake do that returns a
+// 429 once before succeeding, verifying it retries rather than giving up
+// immediately, and that throttle reacts to the low-remaining-quota signal by
+// swapping the limiter down to MinQPS.
+func TestWithRetryRetriesOn429(t *testing.T) {
+	cl := NewDNSClient("test-token", provider.NewDefaultMetrics(), flowcontrol.NewFakeAlwaysRateLimiter(), RateLimiterConfig{
+		MinQPS:     1,
+		MaxQPS:     10,
+		MaxRetries: 3,
+		MaxSleep:   time.Millisecond,
+	})
+
+	calls := 0
+	err := cl.withRetry(func() (*godo.Response, error) {
+		calls++
+		if calls == 1 {
+			resp := &godo.Response{Response: &http.Response{StatusCode: 429}}
+			resp.Rate.Remaining = 0
+			// The reset lies well past the test's end, so the throttled window
+			// is still open when the second call's response is processed;
+			// MaxSleep keeps the actual wait at 1ms.
+			resp.Rate.Reset = godo.Timestamp{Time: time.Now().Add(time.Hour)}
+			return resp, &godo.ErrorResponse{Response: resp.Response}
+		}
+		return &godo.Response{Response: &http.Response{StatusCode: 200}}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected do to be called twice (one 429, one success), got %d calls", calls)
+	}
+
+	cl.limiterMu.Lock()
+	throttled := !cl.throttledUntil.IsZero()
+	cl.limiterMu.Unlock()
+	if !throttled {
+		t.Fatalf("expected throttle to record a throttled-until deadline after a low-remaining response")
+	}
+}
+
+// TestWithRetryGivesUpAfterMaxRetries verifies withRetry stops retrying once
+// MaxRetries is exhausted and surfaces the last error.
+func TestWithRetryGivesUpAfterMaxRetries(t *testing.T) {
+	cl := NewDNSClient("test-token", provider.NewDefaultMetrics(), flowcontrol.NewFakeAlwaysRateLimiter(), RateLimiterConfig{
+		MinQPS:     1,
+		MaxQPS:     10,
+		MaxRetries: 2,
+		MaxSleep:   time.Millisecond,
+	})
+
+	calls := 0
+	err := cl.withRetry(func() (*godo.Response, error) {
+		calls++
+		resp := &godo.Response{Response: &http.Response{StatusCode: 429}}
+		return resp, &godo.ErrorResponse{Response: resp.Response}
+	})
+	if err == nil {
+		t.Fatalf("expected an error after exhausting retries")
+	}
+	if calls != 3 {
+		t.Fatalf("expected 1 initial attempt + 2 retries = 3 calls, got %d", calls)
+	}
+}
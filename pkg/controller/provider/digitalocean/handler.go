@@ -21,6 +21,7 @@ import (
 
 	"github.com/gardener/external-dns-management/pkg/dns"
 	"github.com/gardener/external-dns-management/pkg/dns/provider"
+	"github.com/gardener/external-dns-management/pkg/dns/provider/dnsname"
 	"github.com/gardener/external-dns-management/pkg/dns/provider/raw"
 )
 
@@ -46,7 +47,7 @@ func NewHandler(c *provider.DNSHandlerConfig) (provider.DNSHandler, error) {
 		return nil, err
 	}
 
-	h.client = NewDNSClient(apiToken, c.Metrics, c.RateLimiter)
+	h.client = NewDNSClient(apiToken, c.Metrics, c.RateLimiter, RateLimiterConfigFromProperties(c))
 
 	h.cache, err = provider.NewZoneCache(*c.CacheConfig.CopyWithDisabledZoneStateCache(), c.Metrics, nil, h.getZones, h.getZoneState)
 	if err != nil {
@@ -72,17 +73,24 @@ func (h *Handler) getZones(_ provider.ZoneCache) (provider.DNSHostedZones, error
 
 	zones := provider.DNSHostedZones{}
 
-	var forwarded []string
 	for _, dom := range domains {
 		records, err := h.client.ListRecords(dom.Name)
 		if err != nil {
 			return nil, err
 		}
 
+		var forwarded []string
 		for _, record := range records {
-			if record.Type == dns.RS_NS && record.Name != dom.Name {
-				forwarded = append(forwarded, record.Name)
+			if record.Type != dns.RS_NS {
+				continue
 			}
+
+			subDomain, ok := dnsname.ExtractForwardedSubDomain(record.Name, dom.Name)
+			if !ok {
+				logger.Warnf("ignoring NS record %q in zone %q: not a subdomain of the zone", record.Name, dom.Name)
+				continue
+			}
+			forwarded = append(forwarded, subDomain)
 		}
 
 		hostedZone := provider.NewDNSHostedZone(h.ProviderType(), dom.Name, dom.Name, dom.Name, forwarded, false)
@@ -0,0 +1,208 @@
+/*
+ * Copyright 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ *
+ */
+
+package gcore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/gardener/external-dns-management/pkg/dns/provider"
+	"github.com/gardener/external-dns-management/pkg/dns/provider/raw"
+)
+
+const baseURL = "https://api.gcore.com/dns/v2"
+
+// Zone mirrors the relevant parts of a G-Core Labs DNS v2 zone as returned by
+// GET /v2/zones.
+type Zone struct {
+	Name string `json:"name"`
+}
+
+type zonesResponse struct {
+	Zones []Zone `json:"zones"`
+}
+
+type rrsetsResponse struct {
+	RRSets []RRSet `json:"rrsets"`
+}
+
+// DNSClient talks to the G-Core Labs DNS v2 REST API.
+type DNSClient struct {
+	raw.Executor
+
+	apiToken    string
+	baseURL     string
+	httpClient  *http.Client
+	metrics     provider.Metrics
+	rateLimiter flowcontrol.RateLimiter
+}
+
+func NewDNSClient(apiToken string, metrics provider.Metrics, rateLimiter flowcontrol.RateLimiter) *DNSClient {
+	return &DNSClient{
+		apiToken:    apiToken,
+		baseURL:     baseURL,
+		httpClient:  http.DefaultClient,
+		metrics:     metrics,
+		rateLimiter: rateLimiter,
+	}
+}
+
+func (cl *DNSClient) ListZones() ([]Zone, error) {
+	cl.metrics.AddRequests(provider.M_LISTZONES, 1)
+	cl.rateLimiter.Accept()
+
+	var resp zonesResponse
+	if err := cl.do(http.MethodGet, "/zones", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Zones, nil
+}
+
+func (cl *DNSClient) ListRRSets(zone string) ([]RRSet, error) {
+	cl.metrics.AddRequests(provider.M_LISTRECORDS, 1)
+	cl.rateLimiter.Accept()
+
+	var resp rrsetsResponse
+	if err := cl.do(http.MethodGet, fmt.Sprintf("/zones/%s/rrsets", zone), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.RRSets, nil
+}
+
+func (cl *DNSClient) CreateRecord(r raw.Record) error {
+	rec := r.(*Record)
+	rrset, err := rrsetRequest(rec)
+	if err != nil {
+		return fmt.Errorf("failed to create record of type %q for DNS name %q and value %q: %s", r.GetType(), r.GetDNSName(), r.GetValue(), err)
+	}
+
+	cl.metrics.AddRequests(provider.M_CREATERECORDS, 1)
+	cl.rateLimiter.Accept()
+
+	path := fmt.Sprintf("/zones/%s/%s/%s", rec.zone, rec.name, rec.rtype)
+	if err := cl.do(http.MethodPost, path, rrset, nil); err != nil {
+		return fmt.Errorf("failed to create record of type %q for DNS name %q and value %q: %s", r.GetType(), r.GetDNSName(), r.GetValue(), err)
+	}
+	return nil
+}
+
+func (cl *DNSClient) UpdateRecord(r raw.Record) error {
+	rec := r.(*Record)
+	rrset, err := rrsetRequest(rec)
+	if err != nil {
+		return fmt.Errorf("failed to update record of type %q for DNS name %q and value %q: %s", r.GetType(), r.GetDNSName(), r.GetValue(), err)
+	}
+
+	cl.metrics.AddRequests(provider.M_UPDATERECORDS, 1)
+	cl.rateLimiter.Accept()
+
+	path := fmt.Sprintf("/zones/%s/%s/%s", rec.zone, rec.name, rec.rtype)
+	if err := cl.do(http.MethodPut, path, rrset, nil); err != nil {
+		return fmt.Errorf("failed to update record of type %q for DNS name %q and value %q: %s", r.GetType(), r.GetDNSName(), r.GetValue(), err)
+	}
+	return nil
+}
+
+func (cl *DNSClient) DeleteRecord(r raw.Record) error {
+	rec := r.(*Record)
+
+	cl.metrics.AddRequests(provider.M_DELETERECORDS, 1)
+	cl.rateLimiter.Accept()
+
+	path := fmt.Sprintf("/zones/%s/%s/%s", rec.zone, rec.name, rec.rtype)
+	if err := cl.do(http.MethodDelete, path, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete record of type %q for DNS name %q and value %q: %s", r.GetType(), r.GetDNSName(), r.GetValue(), err)
+	}
+	return nil
+}
+
+func (cl *DNSClient) NewRecord(fqdn, rtype, value string, zone provider.DNSHostedZone, ttl int64) raw.Record {
+	t := int(ttl)
+	if t < 30 {
+		t = 30
+	}
+	return &Record{
+		zone:  zone.Key(),
+		name:  fqdn,
+		rtype: rtype,
+		ttl:   t,
+		value: value,
+	}
+}
+
+func rrsetRequest(r *Record) (RRSet, error) {
+	ttl := r.ttl
+	if ttl < 30 {
+		ttl = 30
+	}
+	content, err := contentFor(r.rtype, r.value)
+	if err != nil {
+		return RRSet{}, err
+	}
+	return RRSet{
+		Name: r.name,
+		Type: r.rtype,
+		TTL:  ttl,
+		ResourceRecords: []ResourceRecord{
+			{Content: content},
+		},
+	}, nil
+}
+
+func (cl *DNSClient) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), method, cl.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "APIKey "+cl.apiToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := cl.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request %s %s failed with status %s: %s", method, path, resp.Status, string(data))
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
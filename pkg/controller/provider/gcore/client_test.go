@@ -0,0 +1,161 @@
+/*
+ * Copyright 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ *
+ */
+
+package gcore
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/gardener/external-dns-management/pkg/dns/provider"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*DNSClient, func()) {
+	srv := httptest.NewServer(handler)
+	cl := NewDNSClient("test-token", provider.NewDefaultMetrics(), flowcontrol.NewFakeAlwaysRateLimiter())
+	cl.httpClient = srv.Client()
+	cl.baseURL = srv.URL
+	return cl, srv.Close
+}
+
+func TestListZones(t *testing.T) {
+	cl, close := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/zones" {
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(zonesResponse{Zones: []Zone{{Name: "example.com"}}})
+	})
+	defer close()
+
+	cl.apiToken = "test-token"
+	zones, err := cl.ListZones()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(zones) != 1 || zones[0].Name != "example.com" {
+		t.Fatalf("unexpected zones: %+v", zones)
+	}
+}
+
+func TestListRRSets(t *testing.T) {
+	cl, close := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/zones/example.com/rrsets" {
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(rrsetsResponse{RRSets: []RRSet{
+			{
+				Name: "www.example.com",
+				Type: "A",
+				TTL:  300,
+				ResourceRecords: []ResourceRecord{
+					{Content: []interface{}{"1.2.3.4"}},
+				},
+			},
+		}})
+	})
+	defer close()
+
+	rrsets, err := cl.ListRRSets("example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(rrsets) != 1 {
+		t.Fatalf("unexpected rrsets: %+v", rrsets)
+	}
+
+	records := toRecords(rrsets[0], "example.com")
+	if len(records) != 1 || records[0].GetValue() != "1.2.3.4" {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+}
+
+func TestCreateRecord(t *testing.T) {
+	var gotMethod, gotPath string
+	cl, close := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	})
+	defer close()
+
+	rec := &Record{zone: "example.com", name: "www.example.com", rtype: "A", ttl: 300, value: "1.2.3.4"}
+	if err := cl.CreateRecord(rec); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if gotMethod != http.MethodPost || gotPath != "/zones/example.com/www.example.com/A" {
+		t.Fatalf("unexpected request: %s %s", gotMethod, gotPath)
+	}
+}
+
+func TestJoinContentAndContentForMultiFieldTypes(t *testing.T) {
+	for _, tc := range []struct {
+		rtype   string
+		content []interface{}
+		value   string
+	}{
+		{rtype: "MX", content: []interface{}{float64(10), "mail.example.com"}, value: "10 mail.example.com"},
+		{rtype: "SRV", content: []interface{}{float64(10), float64(20), float64(5060), "sip.example.com"}, value: "10 20 5060 sip.example.com"},
+		{rtype: "CAA", content: []interface{}{float64(0), "issue", "letsencrypt.org"}, value: "0 issue letsencrypt.org"},
+	} {
+		value, ok := joinContent(tc.rtype, tc.content)
+		if !ok || value != tc.value {
+			t.Fatalf("joinContent(%s, %v) = (%q, %v), want (%q, true)", tc.rtype, tc.content, value, ok, tc.value)
+		}
+
+		content, err := contentFor(tc.rtype, tc.value)
+		if err != nil {
+			t.Fatalf("contentFor(%s, %q) returned unexpected error: %s", tc.rtype, tc.value, err)
+		}
+		if len(content) != len(tc.content) {
+			t.Fatalf("contentFor(%s, %q) = %v, want %d fields", tc.rtype, tc.value, content, len(tc.content))
+		}
+	}
+}
+
+func TestJoinContentRejectsWrongFieldCount(t *testing.T) {
+	if _, ok := joinContent("MX", []interface{}{"only-one-field"}); ok {
+		t.Fatalf("expected joinContent to reject an MX record with only one content field")
+	}
+}
+
+func TestContentForRejectsMalformedValue(t *testing.T) {
+	if _, err := contentFor("SRV", "not-enough-fields"); err == nil {
+		t.Fatalf("expected contentFor to reject a malformed SRV value")
+	}
+}
+
+// TestContentForRejectsTrailingGarbage covers values with more fields than
+// the type requires: fmt.Sscanf alone would stop once its verbs are filled
+// and silently ignore the trailing text, so contentFor must reject this
+// explicitly rather than accepting a truncated parse.
+func TestContentForRejectsTrailingGarbage(t *testing.T) {
+	for _, tc := range []struct {
+		rtype string
+		value string
+	}{
+		{"MX", "10 mail.example.com some garbage"},
+		{"SRV", "10 20 5060 sip.example.com extra"},
+		{"CAA", "0 issue letsencrypt.org extra"},
+	} {
+		if _, err := contentFor(tc.rtype, tc.value); err == nil {
+			t.Errorf("contentFor(%s, %q): expected an error for trailing garbage, got none", tc.rtype, tc.value)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ *
+ */
+
+package gcore
+
+import (
+	"github.com/gardener/controller-manager-library/pkg/logger"
+
+	"github.com/gardener/external-dns-management/pkg/dns"
+	"github.com/gardener/external-dns-management/pkg/dns/provider"
+	"github.com/gardener/external-dns-management/pkg/dns/provider/dnsname"
+	"github.com/gardener/external-dns-management/pkg/dns/provider/raw"
+)
+
+type Handler struct {
+	provider.DefaultDNSHandler
+	config provider.DNSHandlerConfig
+	cache  provider.ZoneCache
+	client *DNSClient
+}
+
+var _ provider.DNSHandler = &Handler{}
+
+func NewHandler(c *provider.DNSHandlerConfig) (provider.DNSHandler, error) {
+	var err error
+
+	h := &Handler{
+		DefaultDNSHandler: provider.NewDefaultDNSHandler(ProviderType),
+		config:            *c,
+	}
+
+	apiToken, err := c.GetRequiredProperty("GCORE_PERMANENT_API_TOKEN", "apiToken")
+	if err != nil {
+		return nil, err
+	}
+
+	h.client = NewDNSClient(apiToken, c.Metrics, c.RateLimiter)
+
+	h.cache, err = provider.NewZoneCache(*c.CacheConfig.CopyWithDisabledZoneStateCache(), c.Metrics, nil, h.getZones, h.getZoneState)
+	if err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+func (h *Handler) Release() {
+	h.cache.Release()
+}
+
+func (h *Handler) GetZones() (provider.DNSHostedZones, error) {
+	return h.cache.GetZones()
+}
+
+func (h *Handler) getZones(_ provider.ZoneCache) (provider.DNSHostedZones, error) {
+	zoneList, err := h.client.ListZones()
+	if err != nil {
+		return nil, err
+	}
+
+	zones := provider.DNSHostedZones{}
+
+	for _, z := range zoneList {
+		var forwarded []string
+		rrsets, err := h.client.ListRRSets(z.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rrset := range rrsets {
+			if rrset.Type != dns.RS_NS {
+				continue
+			}
+
+			subDomain, ok := dnsname.ExtractForwardedSubDomain(rrset.Name, z.Name)
+			if !ok {
+				logger.Warnf("ignoring NS rrset %q in zone %q: not a subdomain of the zone", rrset.Name, z.Name)
+				continue
+			}
+			forwarded = append(forwarded, subDomain)
+		}
+
+		hostedZone := provider.NewDNSHostedZone(h.ProviderType(), z.Name, z.Name, z.Name, forwarded, false)
+		zones = append(zones, hostedZone)
+	}
+
+	return zones, nil
+}
+
+func (h *Handler) GetZoneState(zone provider.DNSHostedZone) (provider.DNSZoneState, error) {
+	return h.cache.GetZoneState(zone)
+}
+
+func (h *Handler) getZoneState(zone provider.DNSHostedZone, _ provider.ZoneCache) (provider.DNSZoneState, error) {
+	rrsets, err := h.client.ListRRSets(zone.Domain())
+	if err != nil {
+		return nil, err
+	}
+
+	state := raw.NewState()
+	for _, rrset := range rrsets {
+		for _, r := range toRecords(rrset, zone.Domain()) {
+			state.AddRecord(r)
+		}
+	}
+	state.CalculateDNSSets()
+
+	return state, nil
+}
+
+func (h *Handler) ReportZoneStateConflict(zone provider.DNSHostedZone, err error) bool {
+	return h.cache.ReportZoneStateConflict(zone, err)
+}
+
+func (h *Handler) ExecuteRequests(logger logger.LogContext, zone provider.DNSHostedZone, state provider.DNSZoneState, reqs []*provider.ChangeRequest) error {
+	err := raw.ExecuteRequests(logger, &h.config, h.client, zone, state, reqs)
+	h.cache.ApplyRequests(err, zone, reqs)
+	return err
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ *
+ */
+
+package gcore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gardener/external-dns-management/pkg/dns/provider/raw"
+)
+
+// RRSet mirrors the relevant parts of a G-Core Labs DNS v2 RRSet as returned
+// by GET /v2/zones/{zone}/rrsets and accepted by the corresponding
+// create/update/delete endpoints.
+type RRSet struct {
+	Name            string           `json:"name"`
+	Type            string           `json:"type"`
+	TTL             int              `json:"ttl"`
+	ResourceRecords []ResourceRecord `json:"resource_records"`
+}
+
+// ResourceRecord is a single content entry of an RRSet's content array. Each
+// entry of Content corresponds to one value (e.g. the address for an A
+// record, or priority/weight/port/target for an SRV record).
+type ResourceRecord struct {
+	Content []interface{} `json:"content"`
+}
+
+// Record adapts a single resource record of a G-Core RRSet to the module's
+// raw.Record interface.
+type Record struct {
+	zone  string
+	name  string
+	rtype string
+	ttl   int
+	value string
+}
+
+var _ raw.Record = &Record{}
+
+func (r *Record) GetType() string {
+	return r.rtype
+}
+
+func (r *Record) GetId() string {
+	return r.name + "/" + r.rtype
+}
+
+func (r *Record) GetDNSName() string {
+	return r.name
+}
+
+func (r *Record) GetValue() string {
+	return r.value
+}
+
+func (r *Record) GetTTL() int {
+	return r.ttl
+}
+
+func (r *Record) Copy() raw.Record {
+	n := *r
+	return &n
+}
+
+// toRecords splits an RRSet into one Record per resource record value, since
+// raw.Record models a single value while G-Core groups all values for a
+// name/type pair into one RRSet. Resource records with a content shape this
+// package doesn't recognize for their type are skipped rather than
+// surfacing a corrupted value.
+func toRecords(rrset RRSet, zone string) []*Record {
+	records := make([]*Record, 0, len(rrset.ResourceRecords))
+	for _, rr := range rrset.ResourceRecords {
+		value, ok := joinContent(rrset.Type, rr.Content)
+		if !ok {
+			continue
+		}
+		records = append(records, &Record{
+			zone:  zone,
+			name:  rrset.Name,
+			rtype: rrset.Type,
+			ttl:   rrset.TTL,
+			value: value,
+		})
+	}
+	return records
+}
+
+// joinContent renders a single resource record's content array as the
+// module's flat string value, using the same "field1 field2 ..." convention
+// the Hosttech provider uses for its own multi-field types. MX is
+// [priority, exchange], SRV is [priority, weight, port, target], and CAA is
+// [flag, tag, value]; every other type is a single opaque value. ok is false
+// if content doesn't have the number of fields rtype requires.
+func joinContent(rtype string, content []interface{}) (value string, ok bool) {
+	switch rtype {
+	case "MX":
+		if len(content) != 2 {
+			return "", false
+		}
+		return fmt.Sprintf("%v %v", content[0], content[1]), true
+	case "SRV":
+		if len(content) != 4 {
+			return "", false
+		}
+		return fmt.Sprintf("%v %v %v %v", content[0], content[1], content[2], content[3]), true
+	case "CAA":
+		if len(content) != 3 {
+			return "", false
+		}
+		return fmt.Sprintf("%v %v %v", content[0], content[1], content[2]), true
+	default:
+		if len(content) != 1 {
+			return "", false
+		}
+		s, ok := content[0].(string)
+		return s, ok
+	}
+}
+
+// contentFor is the inverse of joinContent: it parses value back into the
+// content array G-Core expects for rtype, validating that value has exactly
+// the number of whitespace-separated fields the type requires. This check is
+// done via strings.Fields rather than relying on fmt.Sscanf's return values,
+// since Sscanf stops as soon as its format verbs are filled and silently
+// ignores any trailing text instead of erroring on it.
+func contentFor(rtype, value string) ([]interface{}, error) {
+	switch rtype {
+	case "MX":
+		fields := strings.Fields(value)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid MX value %q: expected \"priority exchange\" (got %d fields)", value, len(fields))
+		}
+		var priority int
+		var exchange string
+		if _, err := fmt.Sscanf(value, "%d %s", &priority, &exchange); err != nil {
+			return nil, fmt.Errorf("invalid MX value %q: expected \"priority exchange\": %s", value, err)
+		}
+		return []interface{}{priority, exchange}, nil
+	case "SRV":
+		fields := strings.Fields(value)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("invalid SRV value %q: expected \"priority weight port target\" (got %d fields)", value, len(fields))
+		}
+		var priority, weight, port int
+		var target string
+		if _, err := fmt.Sscanf(value, "%d %d %d %s", &priority, &weight, &port, &target); err != nil {
+			return nil, fmt.Errorf("invalid SRV value %q: expected \"priority weight port target\": %s", value, err)
+		}
+		return []interface{}{priority, weight, port, target}, nil
+	case "CAA":
+		fields := strings.Fields(value)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid CAA value %q: expected \"flag tag value\" (got %d fields)", value, len(fields))
+		}
+		var flag int
+		var tag, caaValue string
+		if _, err := fmt.Sscanf(value, "%d %s %s", &flag, &tag, &caaValue); err != nil {
+			return nil, fmt.Errorf("invalid CAA value %q: expected \"flag tag value\": %s", value, err)
+		}
+		return []interface{}{flag, tag, caaValue}, nil
+	default:
+		return []interface{}{value}, nil
+	}
+}
@@ -0,0 +1,279 @@
+/*
+ * Copyright 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ *
+ */
+
+package hosttech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/gardener/external-dns-management/pkg/dns/provider"
+	"github.com/gardener/external-dns-management/pkg/dns/provider/raw"
+)
+
+const baseURL = "https://api.ns1.hosttech.eu/api/user/v1"
+
+// Zone mirrors the relevant parts of a Hosttech zone as returned by
+// GET /zones.
+type Zone struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type zonesResponse struct {
+	Data []Zone `json:"data"`
+}
+
+type recordsResponse struct {
+	Data []record `json:"data"`
+}
+
+type recordRequest struct {
+	Data record `json:"data"`
+}
+
+// DNSClient talks to the Hosttech DNS JSON API.
+type DNSClient struct {
+	raw.Executor
+
+	apiKey      string
+	baseURL     string
+	httpClient  *http.Client
+	metrics     provider.Metrics
+	rateLimiter flowcontrol.RateLimiter
+}
+
+func NewDNSClient(apiKey string, metrics provider.Metrics, rateLimiter flowcontrol.RateLimiter) *DNSClient {
+	return &DNSClient{
+		apiKey:      apiKey,
+		baseURL:     baseURL,
+		httpClient:  http.DefaultClient,
+		metrics:     metrics,
+		rateLimiter: rateLimiter,
+	}
+}
+
+func (cl *DNSClient) ListZones() ([]Zone, error) {
+	cl.metrics.AddRequests(provider.M_LISTZONES, 1)
+	cl.rateLimiter.Accept()
+
+	var zones []Zone
+	page := 1
+	for {
+		var resp zonesResponse
+		if err := cl.do(http.MethodGet, fmt.Sprintf("/zones?page=%d", page), nil, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Data) == 0 {
+			break
+		}
+		zones = append(zones, resp.Data...)
+		page++
+	}
+	return zones, nil
+}
+
+func (cl *DNSClient) ListRecords(zoneID int) ([]record, error) {
+	cl.metrics.AddRequests(provider.M_LISTRECORDS, 1)
+	cl.rateLimiter.Accept()
+
+	var records []record
+	page := 1
+	for {
+		var resp recordsResponse
+		if err := cl.do(http.MethodGet, fmt.Sprintf("/zones/%d/records?page=%d", zoneID, page), nil, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Data) == 0 {
+			break
+		}
+		records = append(records, resp.Data...)
+		page++
+	}
+	return records, nil
+}
+
+func (cl *DNSClient) CreateRecord(r raw.Record) error {
+	rec, err := recordFromRaw(r)
+	if err != nil {
+		return fmt.Errorf("failed to create record of type %q for DNS name %q and value %q: %s", r.GetType(), r.GetDNSName(), r.GetValue(), err)
+	}
+
+	cl.metrics.AddRequests(provider.M_CREATERECORDS, 1)
+	cl.rateLimiter.Accept()
+
+	path := fmt.Sprintf("/zones/%s/records", r.(*Record).zone)
+	if err := cl.do(http.MethodPost, path, recordRequest{Data: rec}, nil); err != nil {
+		return fmt.Errorf("failed to create record of type %q for DNS name %q and value %q: %s", r.GetType(), r.GetDNSName(), r.GetValue(), err)
+	}
+	return nil
+}
+
+func (cl *DNSClient) UpdateRecord(r raw.Record) error {
+	rec, err := recordFromRaw(r)
+	if err != nil {
+		return fmt.Errorf("failed to update record of type %q for DNS name %q and value %q: %s", r.GetType(), r.GetDNSName(), r.GetValue(), err)
+	}
+
+	cl.metrics.AddRequests(provider.M_UPDATERECORDS, 1)
+	cl.rateLimiter.Accept()
+
+	path := fmt.Sprintf("/zones/%s/records/%d", r.(*Record).zone, rec.ID)
+	if err := cl.do(http.MethodPut, path, recordRequest{Data: rec}, nil); err != nil {
+		return fmt.Errorf("failed to update record of type %q for DNS name %q and value %q: %s", r.GetType(), r.GetDNSName(), r.GetValue(), err)
+	}
+	return nil
+}
+
+func (cl *DNSClient) DeleteRecord(r raw.Record) error {
+	id, err := strconv.Atoi(r.GetId())
+	if err != nil {
+		return fmt.Errorf("failed to convert record ID %q to integer: %s", r.GetId(), err)
+	}
+
+	cl.metrics.AddRequests(provider.M_DELETERECORDS, 1)
+	cl.rateLimiter.Accept()
+
+	path := fmt.Sprintf("/zones/%s/records/%d", r.(*Record).zone, id)
+	if err := cl.do(http.MethodDelete, path, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete record of type %q for DNS name %q and value %q: %s", r.GetType(), r.GetDNSName(), r.GetValue(), err)
+	}
+	return nil
+}
+
+func (cl *DNSClient) NewRecord(fqdn, rtype, value string, zone provider.DNSHostedZone, ttl int64) raw.Record {
+	t := int(ttl)
+	if t < 30 {
+		t = 30
+	}
+	// A malformed value for a multi-field type (MX/CAA/SRV) is not rejected
+	// here, since this constructor has no error return: the resulting record
+	// keeps its type, name and TTL with its type-specific fields left empty,
+	// and CreateRecord/UpdateRecord re-validate it via recordFromRaw and
+	// refuse to send it to the API.
+	rec, err := valueRecord(rtype, fqdn, value, t)
+	if err != nil {
+		rec = record{Type: rtype, Name: fqdn, TTL: t}
+	}
+	return toRecord(rec, zone.Key())
+}
+
+// valueRecord builds the type-specific wire record for a single display
+// value, the inverse of valueOf. For the multi-field types (MX, CAA, SRV) it
+// returns an error if value doesn't have exactly the number of
+// space-separated fields the type requires, rather than silently leaving
+// fields zeroed or dropping trailing fields that don't fit. The field count
+// is checked via strings.Fields rather than relying solely on fmt.Sscanf's
+// return values, since Sscanf stops as soon as its format verbs are filled
+// and silently ignores any unconsumed trailing text.
+func valueRecord(rtype, name, value string, ttl int) (record, error) {
+	rec := record{Type: rtype, Name: name, TTL: ttl}
+	switch rtype {
+	case "A":
+		rec.IPv4 = value
+	case "AAAA":
+		rec.IPv6 = value
+	case "CNAME":
+		rec.CName = value
+	case "MX":
+		if fields := strings.Fields(value); len(fields) != 2 {
+			return record{}, fmt.Errorf("invalid MX value %q: expected \"priority exchange\" (got %d fields)", value, len(fields))
+		}
+		var prio int
+		var server string
+		if _, err := fmt.Sscanf(value, "%d %s", &prio, &server); err != nil {
+			return record{}, fmt.Errorf("invalid MX value %q: expected \"priority exchange\": %s", value, err)
+		}
+		rec.Priority = prio
+		rec.MailServer = server
+	case "TXT":
+		rec.Text = value
+	case "NS":
+		rec.NameServer = value
+	case "CAA":
+		if fields := strings.Fields(value); len(fields) != 3 {
+			return record{}, fmt.Errorf("invalid CAA value %q: expected \"flag tag value\" (got %d fields)", value, len(fields))
+		}
+		var flag int
+		var tag, caaValue string
+		if _, err := fmt.Sscanf(value, "%d %s %s", &flag, &tag, &caaValue); err != nil {
+			return record{}, fmt.Errorf("invalid CAA value %q: expected \"flag tag value\": %s", value, err)
+		}
+		rec.Flag = flag
+		rec.Tag = tag
+		rec.CAAValue = caaValue
+	case "SRV":
+		if fields := strings.Fields(value); len(fields) != 4 {
+			return record{}, fmt.Errorf("invalid SRV value %q: expected \"priority weight port target\" (got %d fields)", value, len(fields))
+		}
+		var prio, weight, port int
+		var target string
+		if _, err := fmt.Sscanf(value, "%d %d %d %s", &prio, &weight, &port, &target); err != nil {
+			return record{}, fmt.Errorf("invalid SRV value %q: expected \"priority weight port target\": %s", value, err)
+		}
+		rec.Priority = prio
+		rec.Weight = weight
+		rec.Port = port
+		rec.Target = target
+	}
+	return rec, nil
+}
+
+func (cl *DNSClient) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), method, cl.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+cl.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := cl.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request %s %s failed with status %s: %s", method, path, resp.Status, string(data))
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
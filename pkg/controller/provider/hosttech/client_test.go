@@ -0,0 +1,139 @@
+/*
+ * Copyright 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ *
+ */
+
+package hosttech
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/gardener/external-dns-management/pkg/dns/provider"
+)
+
+func newTestClient(handler http.HandlerFunc) (*DNSClient, func()) {
+	srv := httptest.NewServer(handler)
+	cl := NewDNSClient("test-key", provider.NewDefaultMetrics(), flowcontrol.NewFakeAlwaysRateLimiter())
+	cl.httpClient = srv.Client()
+	cl.baseURL = srv.URL
+	return cl, srv.Close
+}
+
+func TestListZonesPaginates(t *testing.T) {
+	calls := 0
+	cl, close := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			_ = json.NewEncoder(w).Encode(zonesResponse{Data: []Zone{{ID: 1, Name: "example.com"}}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(zonesResponse{Data: nil})
+	})
+	defer close()
+
+	zones, err := cl.ListZones()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(zones) != 1 || zones[0].Name != "example.com" {
+		t.Fatalf("unexpected zones: %+v", zones)
+	}
+	if calls != 2 {
+		t.Fatalf("expected pagination to stop after an empty page, got %d calls", calls)
+	}
+}
+
+func TestValueOfAndValueRecordRoundtrip(t *testing.T) {
+	cases := []struct {
+		rtype string
+		value string
+	}{
+		{"A", "1.2.3.4"},
+		{"CNAME", "target.example.com"},
+		{"TXT", "hello world"},
+		{"MX", "10 mail.example.com"},
+		{"CAA", "0 issue letsencrypt.org"},
+		{"SRV", "10 20 5060 sip.example.com"},
+	}
+
+	for _, c := range cases {
+		rec, err := valueRecord(c.rtype, "www.example.com", c.value, 300)
+		if err != nil {
+			t.Fatalf("type %s: unexpected error: %s", c.rtype, err)
+		}
+		if got := valueOf(rec); got != c.value {
+			t.Errorf("type %s: got value %q, want %q", c.rtype, got, c.value)
+		}
+	}
+}
+
+func TestValueRecordRejectsMalformedMultiFieldValues(t *testing.T) {
+	cases := []struct {
+		rtype string
+		value string
+	}{
+		{"MX", "only-priority"},
+		{"CAA", "0 issue"},
+		{"SRV", "10 20 only-three-fields"},
+		// Trailing garbage: fmt.Sscanf alone would stop once its verbs are
+		// filled and silently ignore the extra field, so this must be
+		// rejected explicitly rather than accepted with the excess dropped.
+		{"MX", "10 mail.example.com extra"},
+		{"CAA", "0 issue letsencrypt.org extra"},
+		{"SRV", "10 20 5060 sip.example.com extra"},
+	}
+
+	for _, c := range cases {
+		if _, err := valueRecord(c.rtype, "www.example.com", c.value, 300); err == nil {
+			t.Errorf("type %s: expected an error for malformed value %q, got none", c.rtype, c.value)
+		}
+	}
+}
+
+func TestCreateRecordRejectsMalformedValue(t *testing.T) {
+	cl, close := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("request should not have been sent for a malformed record value")
+	})
+	defer close()
+
+	// An MX record whose MailServer was never populated renders as an
+	// incomplete value ("0 ") that recordFromRaw must reject rather than
+	// silently reconstructing a zeroed record and sending it to the API.
+	rec := toRecord(record{Type: "MX", Name: "mx.example.com", TTL: 300, Priority: 0}, "42")
+	if err := cl.CreateRecord(rec); err == nil {
+		t.Fatalf("expected CreateRecord to reject a malformed MX value instead of silently sending a partial record")
+	}
+}
+
+func TestCreateRecordRequestPath(t *testing.T) {
+	var gotPath string
+	cl, close := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer close()
+
+	rec := toRecord(record{Type: "A", Name: "www.example.com", TTL: 300, IPv4: "1.2.3.4"}, "42")
+	if err := cl.CreateRecord(rec); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if gotPath != "/zones/42/records" {
+		t.Fatalf("unexpected path: %s", gotPath)
+	}
+}
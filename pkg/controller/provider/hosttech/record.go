@@ -0,0 +1,125 @@
+/*
+ * Copyright 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ *
+ */
+
+package hosttech
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gardener/external-dns-management/pkg/dns/provider/raw"
+)
+
+// record is the wire shape of a single entry returned by (and accepted by)
+// https://api.ns1.hosttech.eu/api/user/v1/zones/{zone}/records. Hosttech
+// keeps a distinct JSON payload per record type, so most fields are only
+// populated for the types that use them.
+type record struct {
+	ID         int    `json:"id,omitempty"`
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	TTL        int    `json:"ttl"`
+	IPv4       string `json:"ipv4,omitempty"`       // A
+	IPv6       string `json:"ipv6,omitempty"`       // AAAA
+	CName      string `json:"cname,omitempty"`      // CNAME
+	MailServer string `json:"mailserver,omitempty"` // MX
+	Priority   int    `json:"priority,omitempty"`   // MX, SRV
+	Text       string `json:"text,omitempty"`       // TXT
+	NameServer string `json:"nameserver,omitempty"` // NS
+	Flag       int    `json:"flag,omitempty"`       // CAA
+	Tag        string `json:"tag,omitempty"`        // CAA
+	CAAValue   string `json:"value,omitempty"`      // CAA
+	Weight     int    `json:"weight,omitempty"`     // SRV
+	Port       int    `json:"port,omitempty"`       // SRV
+	Target     string `json:"target,omitempty"`     // SRV
+}
+
+// Record adapts a Hosttech record to the module's raw.Record interface.
+type Record struct {
+	record
+	zone string
+}
+
+var _ raw.Record = &Record{}
+
+func (r *Record) GetType() string {
+	return r.Type
+}
+
+func (r *Record) GetId() string {
+	return strconv.Itoa(r.ID)
+}
+
+func (r *Record) GetDNSName() string {
+	return r.Name
+}
+
+func (r *Record) GetValue() string {
+	return valueOf(r.record)
+}
+
+func (r *Record) GetTTL() int {
+	return r.TTL
+}
+
+func (r *Record) Copy() raw.Record {
+	n := *r
+	return &n
+}
+
+func toRecord(rec record, zone string) *Record {
+	return &Record{record: rec, zone: zone}
+}
+
+// valueOf extracts the single display value of a record, regardless of which
+// type-specific field it is stored in.
+func valueOf(rec record) string {
+	switch rec.Type {
+	case "A":
+		return rec.IPv4
+	case "AAAA":
+		return rec.IPv6
+	case "CNAME":
+		return rec.CName
+	case "MX":
+		return fmt.Sprintf("%d %s", rec.Priority, rec.MailServer)
+	case "TXT":
+		return rec.Text
+	case "NS":
+		return rec.NameServer
+	case "CAA":
+		return fmt.Sprintf("%d %s %s", rec.Flag, rec.Tag, rec.CAAValue)
+	case "SRV":
+		return fmt.Sprintf("%d %d %d %s", rec.Priority, rec.Weight, rec.Port, rec.Target)
+	default:
+		return ""
+	}
+}
+
+// recordFromRaw fills the type-specific field(s) of a record from a generic
+// raw.Record, mirroring valueOf in reverse. It returns an error rather than
+// a partially-populated record if r's value doesn't have the number of
+// space-separated fields its type requires.
+func recordFromRaw(r raw.Record) (record, error) {
+	rec, err := valueRecord(r.GetType(), r.GetDNSName(), r.GetValue(), r.GetTTL())
+	if err != nil {
+		return record{}, err
+	}
+	if cur, ok := r.(*Record); ok {
+		rec.ID = cur.ID
+	}
+	return rec, nil
+}
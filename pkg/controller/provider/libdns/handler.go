@@ -0,0 +1,197 @@
+/*
+ * Copyright 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ *
+ */
+
+package libdns
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gardener/controller-manager-library/pkg/logger"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/gardener/external-dns-management/pkg/dns"
+	"github.com/gardener/external-dns-management/pkg/dns/provider"
+	"github.com/gardener/external-dns-management/pkg/dns/provider/dnsname"
+	"github.com/gardener/external-dns-management/pkg/dns/provider/raw"
+)
+
+// client wraps a libdns.Provider as raw.Executor, applying the same
+// metrics/rate-limiter plumbing the hand-written providers in this module
+// use.
+type client struct {
+	raw.Executor
+
+	backend     Provider
+	metrics     provider.Metrics
+	rateLimiter flowcontrol.RateLimiter
+}
+
+func (cl *client) ListZones() ([]Zone, error) {
+	cl.metrics.AddRequests(provider.M_LISTZONES, 1)
+	cl.rateLimiter.Accept()
+	return cl.backend.ListZones(context.Background())
+}
+
+func (cl *client) ListRecords(zone string) ([]Record, error) {
+	cl.metrics.AddRequests(provider.M_LISTRECORDS, 1)
+	cl.rateLimiter.Accept()
+	return cl.backend.GetRecords(context.Background(), zone)
+}
+
+func (cl *client) CreateRecord(r raw.Record) error {
+	cl.metrics.AddRequests(provider.M_CREATERECORDS, 1)
+	cl.rateLimiter.Accept()
+
+	rec := r.(*record)
+	if _, err := cl.backend.AppendRecords(context.Background(), rec.zone, []Record{toRecord(r)}); err != nil {
+		return fmt.Errorf("failed to create record of type %q for DNS name %q and value %q: %s", r.GetType(), r.GetDNSName(), r.GetValue(), err)
+	}
+	return nil
+}
+
+func (cl *client) UpdateRecord(r raw.Record) error {
+	cl.metrics.AddRequests(provider.M_UPDATERECORDS, 1)
+	cl.rateLimiter.Accept()
+
+	rec := r.(*record)
+	if _, err := cl.backend.SetRecords(context.Background(), rec.zone, []Record{toRecord(r)}); err != nil {
+		return fmt.Errorf("failed to update record of type %q for DNS name %q and value %q: %s", r.GetType(), r.GetDNSName(), r.GetValue(), err)
+	}
+	return nil
+}
+
+func (cl *client) DeleteRecord(r raw.Record) error {
+	cl.metrics.AddRequests(provider.M_DELETERECORDS, 1)
+	cl.rateLimiter.Accept()
+
+	rec := r.(*record)
+	if _, err := cl.backend.DeleteRecords(context.Background(), rec.zone, []Record{toRecord(r)}); err != nil {
+		return fmt.Errorf("failed to delete record of type %q for DNS name %q and value %q: %s", r.GetType(), r.GetDNSName(), r.GetValue(), err)
+	}
+	return nil
+}
+
+func (cl *client) NewRecord(fqdn, rtype, value string, zone provider.DNSHostedZone, ttl int64) raw.Record {
+	return fromRecord(Record{Name: fqdn, Type: rtype, Value: value, TTL: clampTTL(time.Duration(ttl) * time.Second)}, zone.Key())
+}
+
+// Handler is the provider.DNSHandler implementation shared by every libdns
+// backend registered via Register.
+type Handler struct {
+	provider.DefaultDNSHandler
+	config provider.DNSHandlerConfig
+	cache  provider.ZoneCache
+	client *client
+}
+
+var _ provider.DNSHandler = &Handler{}
+
+// NewHandler wraps backend, a libdns.Provider, as a provider.DNSHandler for
+// providerType.
+func NewHandler(providerType string, backend Provider, c *provider.DNSHandlerConfig) (provider.DNSHandler, error) {
+	var err error
+
+	h := &Handler{
+		DefaultDNSHandler: provider.NewDefaultDNSHandler(providerType),
+		config:            *c,
+		client: &client{
+			backend:     backend,
+			metrics:     c.Metrics,
+			rateLimiter: c.RateLimiter,
+		},
+	}
+
+	h.cache, err = provider.NewZoneCache(*c.CacheConfig.CopyWithDisabledZoneStateCache(), c.Metrics, nil, h.getZones, h.getZoneState)
+	if err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+func (h *Handler) Release() {
+	h.cache.Release()
+}
+
+func (h *Handler) GetZones() (provider.DNSHostedZones, error) {
+	return h.cache.GetZones()
+}
+
+func (h *Handler) getZones(_ provider.ZoneCache) (provider.DNSHostedZones, error) {
+	zoneList, err := h.client.ListZones()
+	if err != nil {
+		return nil, err
+	}
+
+	zones := provider.DNSHostedZones{}
+
+	for _, z := range zoneList {
+		var forwarded []string
+		records, err := h.client.ListRecords(z.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rec := range records {
+			if rec.Type != dns.RS_NS {
+				continue
+			}
+
+			subDomain, ok := dnsname.ExtractForwardedSubDomain(rec.Name, z.Name)
+			if !ok {
+				logger.Warnf("ignoring NS record %q in zone %q: not a subdomain of the zone", rec.Name, z.Name)
+				continue
+			}
+			forwarded = append(forwarded, subDomain)
+		}
+
+		hostedZone := provider.NewDNSHostedZone(h.ProviderType(), z.Name, z.Name, z.Name, forwarded, false)
+		zones = append(zones, hostedZone)
+	}
+
+	return zones, nil
+}
+
+func (h *Handler) GetZoneState(zone provider.DNSHostedZone) (provider.DNSZoneState, error) {
+	return h.cache.GetZoneState(zone)
+}
+
+func (h *Handler) getZoneState(zone provider.DNSHostedZone, _ provider.ZoneCache) (provider.DNSZoneState, error) {
+	records, err := h.client.ListRecords(zone.Domain())
+	if err != nil {
+		return nil, err
+	}
+
+	state := raw.NewState()
+	for _, rec := range records {
+		state.AddRecord(fromRecord(rec, zone.Domain()))
+	}
+	state.CalculateDNSSets()
+
+	return state, nil
+}
+
+func (h *Handler) ReportZoneStateConflict(zone provider.DNSHostedZone, err error) bool {
+	return h.cache.ReportZoneStateConflict(zone, err)
+}
+
+func (h *Handler) ExecuteRequests(logger logger.LogContext, zone provider.DNSHostedZone, state provider.DNSZoneState, reqs []*provider.ChangeRequest) error {
+	err := raw.ExecuteRequests(logger, &h.config, h.client, zone, state, reqs)
+	h.cache.ApplyRequests(err, zone, reqs)
+	return err
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ *
+ */
+
+// Package libdns adapts libdns (https://github.com/libdns/libdns)
+// implementations into the module's provider.DNSHandler / raw.Executor
+// pattern, so a single generic handler can serve any backend that only
+// speaks the small libdns interfaces instead of requiring a bespoke
+// pkg/controller/provider/<name> package per backend.
+package libdns
+
+import (
+	"context"
+	"time"
+)
+
+// Record is libdns' minimal record shape. It mirrors the Name/Type/Value/TTL
+// fields of github.com/libdns/libdns.Record but is a distinct type: a
+// provider written against that package does not satisfy the interfaces
+// below as is, and needs a wrapper that converts its records to and from
+// this type.
+type Record struct {
+	Name  string
+	Type  string
+	Value string
+	TTL   time.Duration
+}
+
+// Zone is libdns' minimal zone shape, as returned by ZoneLister.
+type Zone struct {
+	Name string
+}
+
+// RecordGetter is implemented by libdns providers that can list the records
+// of a zone.
+type RecordGetter interface {
+	GetRecords(ctx context.Context, zone string) ([]Record, error)
+}
+
+// RecordAppender is implemented by libdns providers that can create new
+// records.
+type RecordAppender interface {
+	AppendRecords(ctx context.Context, zone string, recs []Record) ([]Record, error)
+}
+
+// RecordSetter is implemented by libdns providers that can create or
+// overwrite records.
+type RecordSetter interface {
+	SetRecords(ctx context.Context, zone string, recs []Record) ([]Record, error)
+}
+
+// RecordDeleter is implemented by libdns providers that can delete records.
+type RecordDeleter interface {
+	DeleteRecords(ctx context.Context, zone string, recs []Record) ([]Record, error)
+}
+
+// ZoneLister is implemented by libdns providers that can enumerate the zones
+// available to the configured credentials.
+type ZoneLister interface {
+	ListZones(ctx context.Context) ([]Zone, error)
+}
+
+// Provider is the full set of libdns capabilities this adapter requires,
+// expressed in this package's Record and Zone types.
+type Provider interface {
+	RecordGetter
+	RecordAppender
+	RecordSetter
+	RecordDeleter
+	ZoneLister
+}
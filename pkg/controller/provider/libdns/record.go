@@ -0,0 +1,87 @@
+/*
+ * Copyright 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ *
+ */
+
+package libdns
+
+import (
+	"time"
+
+	"github.com/gardener/external-dns-management/pkg/dns/provider/raw"
+)
+
+// minTTL is the lower bound applied by clampTTL. It mirrors the `< 30`
+// clamp digitalocean's createRecordRequest applies, since most libdns
+// backends reject or silently round up sub-30s TTLs the same way.
+const minTTL = 30 * time.Second
+
+// clampTTL rounds ttl up to minTTL if it is lower. Backends with a stricter
+// minimum can wrap a Factory's records through their own clamp before
+// handing them to AppendRecords/SetRecords.
+func clampTTL(ttl time.Duration) time.Duration {
+	if ttl < minTTL {
+		return minTTL
+	}
+	return ttl
+}
+
+// record adapts a libdns.Record to the module's raw.Record interface.
+type record struct {
+	zone string
+	Record
+}
+
+var _ raw.Record = &record{}
+
+func (r *record) GetType() string {
+	return r.Type
+}
+
+func (r *record) GetId() string {
+	return r.Name + "/" + r.Type + "/" + r.Value
+}
+
+func (r *record) GetDNSName() string {
+	return r.Name
+}
+
+func (r *record) GetValue() string {
+	return r.Value
+}
+
+func (r *record) GetTTL() int {
+	return int(r.TTL.Seconds())
+}
+
+func (r *record) Copy() raw.Record {
+	n := *r
+	return &n
+}
+
+// toRecord converts a raw.Record into a libdns.Record, clamping its TTL.
+func toRecord(r raw.Record) Record {
+	return Record{
+		Name:  r.GetDNSName(),
+		Type:  r.GetType(),
+		Value: r.GetValue(),
+		TTL:   clampTTL(time.Duration(r.GetTTL()) * time.Second),
+	}
+}
+
+// fromRecord converts a libdns.Record returned by a backend into the
+// module's raw.Record interface.
+func fromRecord(rec Record, zone string) *record {
+	return &record{zone: zone, Record: rec}
+}
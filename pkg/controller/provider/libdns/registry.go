@@ -0,0 +1,86 @@
+/*
+ * Copyright 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ *
+ */
+
+package libdns
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gardener/external-dns-management/pkg/dns/provider"
+	"github.com/gardener/external-dns-management/pkg/dns/provider/compound"
+)
+
+// Factory builds a libdns Provider from a DNSHandlerConfig. Backends
+// register one of these under a name at init time to get a fully working
+// DNSProvider type without writing metrics/rate-limit/zone-cache glue
+// themselves.
+type Factory func(c *provider.DNSHandlerConfig) (Provider, error)
+
+var (
+	lock      sync.Mutex
+	factories = map[string]Factory{}
+)
+
+// Register makes a libdns backend available as a DNSProvider type. It is
+// meant to be called from the init() function of a small package, e.g.:
+//
+//	func init() {
+//		libdns.Register("hetzner", func(c *provider.DNSHandlerConfig) (libdns.Provider, error) {
+//			apiToken, err := c.GetRequiredProperty("HETZNER_API_TOKEN", "apiToken")
+//			if err != nil {
+//				return nil, err
+//			}
+//			// hetznerAdapter converts between hetzner.Provider's records and
+//			// this package's Record type.
+//			return &hetznerAdapter{p: &hetzner.Provider{APIToken: apiToken}}, nil
+//		})
+//	}
+//
+// Register panics if name is already registered, the same way the compound
+// registry rejects duplicate provider types.
+func Register(name string, factory Factory) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, exists := factories[name]; exists {
+		panic(fmt.Sprintf("libdns provider %q already registered", name))
+	}
+	factories[name] = factory
+
+	compound.MustRegisterHandlerFactory(compound.NewDNSHandlerCompoundFactory(name, newHandlerFactory(name, factory)))
+}
+
+func newHandlerFactory(name string, factory Factory) func(c *provider.DNSHandlerConfig) (provider.DNSHandler, error) {
+	return func(c *provider.DNSHandlerConfig) (provider.DNSHandler, error) {
+		backend, err := factory(c)
+		if err != nil {
+			return nil, err
+		}
+		return NewHandler(name, backend, c)
+	}
+}
+
+// Get looks up a previously registered backend factory by name. It is
+// mainly useful for tests that want to exercise the registry without going
+// through the compound registration side effect.
+func Get(name string) (Factory, bool) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	factory, ok := factories[name]
+	return factory, ok
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ *
+ */
+
+package libdns
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/gardener/external-dns-management/pkg/dns/provider"
+)
+
+// fakeProvider is an in-memory libdns.Provider used to exercise the adapter
+// without a real backend.
+type fakeProvider struct {
+	zones   []Zone
+	records map[string][]Record
+}
+
+var _ Provider = &fakeProvider{}
+
+func (f *fakeProvider) ListZones(_ context.Context) ([]Zone, error) {
+	return f.zones, nil
+}
+
+func (f *fakeProvider) GetRecords(_ context.Context, zone string) ([]Record, error) {
+	return f.records[zone], nil
+}
+
+func (f *fakeProvider) AppendRecords(_ context.Context, zone string, recs []Record) ([]Record, error) {
+	f.records[zone] = append(f.records[zone], recs...)
+	return recs, nil
+}
+
+func (f *fakeProvider) SetRecords(_ context.Context, zone string, recs []Record) ([]Record, error) {
+	f.records[zone] = recs
+	return recs, nil
+}
+
+func (f *fakeProvider) DeleteRecords(_ context.Context, zone string, recs []Record) ([]Record, error) {
+	var kept []Record
+	for _, existing := range f.records[zone] {
+		remove := false
+		for _, r := range recs {
+			if existing.Name == r.Name && existing.Type == r.Type && existing.Value == r.Value {
+				remove = true
+				break
+			}
+		}
+		if !remove {
+			kept = append(kept, existing)
+		}
+	}
+	f.records[zone] = kept
+	return recs, nil
+}
+
+func TestRegisterAndGet(t *testing.T) {
+	if _, ok := Get("faketest"); ok {
+		t.Fatalf("faketest should not be registered yet")
+	}
+
+	Register("faketest", func(c *provider.DNSHandlerConfig) (Provider, error) {
+		return &fakeProvider{records: map[string][]Record{}}, nil
+	})
+
+	factory, ok := Get("faketest")
+	if !ok {
+		t.Fatalf("expected faketest to be registered")
+	}
+
+	backend, err := factory(&provider.DNSHandlerConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if backend == nil {
+		t.Fatalf("expected a non-nil backend")
+	}
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	Register("faketest-dup", func(c *provider.DNSHandlerConfig) (Provider, error) {
+		return &fakeProvider{records: map[string][]Record{}}, nil
+	})
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected Register to panic on duplicate name")
+		}
+	}()
+	Register("faketest-dup", func(c *provider.DNSHandlerConfig) (Provider, error) {
+		return &fakeProvider{records: map[string][]Record{}}, nil
+	})
+}
+
+func TestClampTTL(t *testing.T) {
+	if got := clampTTL(5 * time.Second); got != minTTL {
+		t.Fatalf("expected clamp to %s, got %s", minTTL, got)
+	}
+	if got := clampTTL(60 * time.Second); got != 60*time.Second {
+		t.Fatalf("expected TTL to pass through unchanged, got %s", got)
+	}
+}
+
+func TestRecordConversionRoundtrip(t *testing.T) {
+	fake := &fakeProvider{records: map[string][]Record{}}
+	cl := &client{backend: fake, metrics: provider.NewDefaultMetrics(), rateLimiter: flowcontrol.NewFakeAlwaysRateLimiter()}
+
+	r := fromRecord(Record{Name: "www.example.com", Type: "A", Value: "1.2.3.4", TTL: clampTTL(10 * time.Second)}, "example.com")
+	if err := cl.CreateRecord(r); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	records, err := cl.ListRecords("example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(records) != 1 || records[0].Value != "1.2.3.4" {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+	if records[0].TTL != minTTL {
+		t.Fatalf("expected TTL to be clamped to %s, got %s", minTTL, records[0].TTL)
+	}
+}
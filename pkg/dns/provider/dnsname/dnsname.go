@@ -0,0 +1,60 @@
+/*
+ * Copyright 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ *
+ */
+
+// Package dnsname holds small DNS name comparison helpers shared by the
+// provider backends in pkg/controller/provider, including the libdns
+// adapter.
+package dnsname
+
+import (
+	"strings"
+)
+
+// Canonical returns name with a trailing dot, the same fully-qualified form
+// used by dns01.ExtractSubDomain in the ACME ecosystem to compare names
+// independent of whether they were given rooted or not.
+func Canonical(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// ExtractForwardedSubDomain validates that recordName is a strict subdomain
+// of zoneName and, if so, returns the subdomain label to record as a
+// forwarded NS delegation (e.g. "ns" for record "ns.example.com" and zone
+// "example.com"). It returns false for a record equal to the zone itself or
+// for one that isn't a subdomain of it at all (e.g. a differently-suffixed
+// name that merely happens to share a string suffix).
+func ExtractForwardedSubDomain(recordName, zoneName string) (string, bool) {
+	canonRecord := Canonical(recordName)
+	canonZone := Canonical(zoneName)
+
+	if canonRecord == canonZone {
+		return "", false
+	}
+
+	if !strings.HasSuffix(canonRecord, canonZone) {
+		return "", false
+	}
+
+	boundary := len(canonRecord) - len(canonZone)
+	if boundary <= 0 || canonRecord[boundary-1] != '.' {
+		return "", false
+	}
+
+	return canonRecord[:boundary-1], true
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ *
+ */
+
+package dnsname
+
+import "testing"
+
+func TestExtractForwardedSubDomain(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		recordName string
+		zoneName   string
+		wantSub    string
+		wantOK     bool
+	}{
+		{
+			name:       "equal name is rejected",
+			recordName: "example.com",
+			zoneName:   "example.com",
+			wantOK:     false,
+		},
+		{
+			name:       "equal name with trailing dot is rejected",
+			recordName: "example.com.",
+			zoneName:   "example.com",
+			wantOK:     false,
+		},
+		{
+			name:       "non-subdomain sharing a string suffix is rejected",
+			recordName: "notexample.com",
+			zoneName:   "example.com",
+			wantOK:     false,
+		},
+		{
+			name:       "direct subdomain is accepted",
+			recordName: "ns.example.com",
+			zoneName:   "example.com",
+			wantSub:    "ns",
+			wantOK:     true,
+		},
+		{
+			name:       "deep subdomain is accepted",
+			recordName: "ns1.sub.example.com",
+			zoneName:   "example.com",
+			wantSub:    "ns1.sub",
+			wantOK:     true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sub, ok := ExtractForwardedSubDomain(tc.recordName, tc.zoneName)
+			if ok != tc.wantOK {
+				t.Fatalf("ExtractForwardedSubDomain(%q, %q) ok = %v, want %v", tc.recordName, tc.zoneName, ok, tc.wantOK)
+			}
+			if ok && sub != tc.wantSub {
+				t.Fatalf("ExtractForwardedSubDomain(%q, %q) = %q, want %q", tc.recordName, tc.zoneName, sub, tc.wantSub)
+			}
+		})
+	}
+}
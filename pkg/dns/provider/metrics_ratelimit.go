@@ -0,0 +1,28 @@
+/*
+ * Copyright 2020 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ *
+ */
+
+package provider
+
+// M_THROTTLED and M_RETRIED join the existing M_LISTZONES/M_LISTRECORDS/
+// M_CREATERECORDS/M_UPDATERECORDS/M_DELETERECORDS request counters. They are
+// reported by providers that react to upstream rate-limiting: M_THROTTLED
+// when a request's rate limiter is throttled down in response to a
+// low-remaining-quota signal, M_RETRIED when a request is retried after a
+// 429 Too Many Requests response.
+const (
+	M_THROTTLED = "throttled_requests"
+	M_RETRIED   = "retried_requests"
+)